@@ -0,0 +1,168 @@
+package scim
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"golang.org/x/oauth2"
+)
+
+// authMiddleware wraps a RoundTripper with an authentication scheme.
+type authMiddleware func(next http.RoundTripper) http.RoundTripper
+
+// WithBearerToken installs an OAuth2 bearer token transport that attaches
+// "Authorization: Bearer <token>" to every outgoing request, refreshing
+// the token per source's TokenSource contract.
+func WithBearerToken(source oauth2.TokenSource) ClientOpt {
+	return func(cfg *clientCfg) error {
+		cfg.authMiddleware = func(next http.RoundTripper) http.RoundTripper {
+			return &oauth2.Transport{Source: source, Base: next}
+		}
+		return nil
+	}
+}
+
+// WithBasicAuth installs an HTTP Basic authentication transport.
+func WithBasicAuth(user, pass string) ClientOpt {
+	return func(cfg *clientCfg) error {
+		cfg.authMiddleware = func(next http.RoundTripper) http.RoundTripper {
+			return basicAuthTransport{user: user, pass: pass, next: next}
+		}
+		return nil
+	}
+}
+
+type basicAuthTransport struct {
+	user, pass string
+	next       http.RoundTripper
+}
+
+func (t basicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = cloneRequest(req)
+	req.SetBasicAuth(t.user, t.pass)
+
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
+
+func cloneRequest(req *http.Request) *http.Request {
+	clone := new(http.Request)
+	*clone = *req
+	clone.Header = req.Header.Clone()
+	return clone
+}
+
+// MTLSOpt configures the TLS behavior installed by WithMutualTLS.
+type MTLSOpt func(*mtlsCfg)
+
+type mtlsCfg struct {
+	legacyCommonName string
+}
+
+// AcceptLegacyCommonName permits a server certificate lacking any SAN
+// entries to verify successfully if its Subject CommonName matches name.
+// This exists solely for older SCIM appliances that still issue
+// CN-only certificates; prefer SAN-based verification wherever possible.
+func AcceptLegacyCommonName(name string) MTLSOpt {
+	return func(cfg *mtlsCfg) {
+		cfg.legacyCommonName = name
+	}
+}
+
+// WithMutualTLS installs an *http.Transport configured for mutual TLS
+// using the given client certificate and (optional) custom root CA pool.
+func WithMutualTLS(cert tls.Certificate, rootCAs *x509.CertPool, opts ...MTLSOpt) ClientOpt {
+	mCfg := mtlsCfg{}
+	for _, opt := range opts {
+		opt(&mCfg)
+	}
+
+	return func(cfg *clientCfg) error {
+		tlsCfg := &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			RootCAs:      rootCAs,
+		}
+		if mCfg.legacyCommonName != "" {
+			tlsCfg.InsecureSkipVerify = true
+			tlsCfg.VerifyConnection = legacyCommonNameVerifier(rootCAs, mCfg.legacyCommonName)
+		}
+		cfg.baseTransport = &http.Transport{TLSClientConfig: tlsCfg}
+		return nil
+	}
+}
+
+// mutualTLSOptFromEnv builds a WithMutualTLS ClientOpt from the
+// SCIM_CLIENT_CERT, SCIM_CLIENT_KEY and SCIM_CA_BUNDLE environment
+// variables, returning a nil opt if no client certificate is configured.
+func mutualTLSOptFromEnv() (ClientOpt, error) {
+	certFile := os.Getenv("SCIM_CLIENT_CERT")
+	keyFile := os.Getenv("SCIM_CLIENT_KEY")
+	if certFile == "" || keyFile == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots *x509.CertPool
+	if caFile := os.Getenv("SCIM_CA_BUNDLE"); caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("scim: no certificates found in CA bundle %q", caFile)
+		}
+	}
+
+	return WithMutualTLS(cert, roots), nil
+}
+
+// legacyCommonNameVerifier replicates Go's normal chain and hostname
+// verification, but additionally accepts a SAN-less leaf certificate whose
+// Subject CommonName matches allowedCN, for appliances that predate RFC
+// 6125. It's installed as VerifyConnection (rather than
+// VerifyPeerCertificate) so it has access to the dialed ServerName needed
+// to perform that normal verification itself once InsecureSkipVerify
+// disables it.
+func legacyCommonNameVerifier(roots *x509.CertPool, allowedCN string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("scim: server presented no certificate")
+		}
+		leaf := cs.PeerCertificates[0]
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		if len(leaf.DNSNames) == 0 && len(leaf.IPAddresses) == 0 {
+			if leaf.Subject.CommonName != allowedCN {
+				return fmt.Errorf("scim: server certificate has no SAN and CommonName is not the configured legacy name %q", allowedCN)
+			}
+			opts := x509.VerifyOptions{Roots: roots, Intermediates: intermediates}
+			if _, err := leaf.Verify(opts); err != nil {
+				return fmt.Errorf("scim: legacy CommonName %q did not chain to a trusted root: %w", allowedCN, err)
+			}
+			return nil
+		}
+
+		opts := x509.VerifyOptions{Roots: roots, Intermediates: intermediates, DNSName: cs.ServerName}
+		if _, err := leaf.Verify(opts); err != nil {
+			return fmt.Errorf("scim: server certificate failed verification: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,176 @@
+package scim
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithBasicAuth(t *testing.T) {
+	var gotUser, gotPass string
+	var gotAuthOK bool
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotUser, gotPass, gotAuthOK = req.BasicAuth()
+		return &http.Response{StatusCode: 200}, nil
+	})
+
+	transport := basicAuthTransport{user: "svc", pass: "s3cr3t", next: base}
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/scim/Users", nil)
+	assert.NoError(t, err)
+
+	_, err = transport.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.True(t, gotAuthOK)
+	assert.Equal(t, "svc", gotUser)
+	assert.Equal(t, "s3cr3t", gotPass)
+
+	assert.Empty(t, req.Header.Get("Authorization"))
+}
+
+// selfSignedCA generates an in-memory root CA certificate and key for use
+// as a trust anchor in tests.
+func selfSignedCA(t *testing.T) (*x509.Certificate, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test root CA"},
+		NotBefore:             time.Unix(0, 0),
+		NotAfter:              time.Unix(0, 0).Add(100 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert, key
+}
+
+// sanLessLeaf generates an in-memory leaf certificate with no SAN entries,
+// signed by ca, carrying the given Subject CommonName.
+func sanLessLeaf(t *testing.T, ca *x509.Certificate, caKey *rsa.PrivateKey, commonName string) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(100 * 365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	assert.NoError(t, err)
+	return der
+}
+
+// sanLeaf generates an in-memory leaf certificate with the given DNS SAN,
+// signed by ca.
+func sanLeaf(t *testing.T, ca *x509.Certificate, caKey *rsa.PrivateKey, dnsName string) *x509.Certificate {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(4),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(100 * 365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert
+}
+
+func TestLegacyCommonNameVerifier(t *testing.T) {
+	trustedCA, trustedKey := selfSignedCA(t)
+	roots := x509.NewCertPool()
+	roots.AddCert(trustedCA)
+
+	untrustedCA, untrustedKey := selfSignedCA(t)
+
+	t.Run("no certificate presented", func(t *testing.T) {
+		verify := legacyCommonNameVerifier(roots, "legacy-appliance")
+		err := verify(tls.ConnectionState{})
+		assert.Error(t, err)
+	})
+
+	t.Run("SAN-less leaf with matching CN and trusted chain is accepted", func(t *testing.T) {
+		leafDER := sanLessLeaf(t, trustedCA, trustedKey, "legacy-appliance")
+		leaf, err := x509.ParseCertificate(leafDER)
+		assert.NoError(t, err)
+		verify := legacyCommonNameVerifier(roots, "legacy-appliance")
+		err = verify(tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("CN mismatch is rejected", func(t *testing.T) {
+		leafDER := sanLessLeaf(t, trustedCA, trustedKey, "some-other-appliance")
+		leaf, err := x509.ParseCertificate(leafDER)
+		assert.NoError(t, err)
+		verify := legacyCommonNameVerifier(roots, "legacy-appliance")
+		err = verify(tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}})
+		assert.Error(t, err)
+	})
+
+	t.Run("untrusted root is rejected", func(t *testing.T) {
+		leafDER := sanLessLeaf(t, untrustedCA, untrustedKey, "legacy-appliance")
+		leaf, err := x509.ParseCertificate(leafDER)
+		assert.NoError(t, err)
+		verify := legacyCommonNameVerifier(roots, "legacy-appliance")
+		err = verify(tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}})
+		assert.Error(t, err)
+	})
+
+	t.Run("SAN-bearing leaf with matching hostname and trusted chain is accepted", func(t *testing.T) {
+		leaf := sanLeaf(t, trustedCA, trustedKey, "scim.example.com")
+		verify := legacyCommonNameVerifier(roots, "legacy-appliance")
+		err := verify(tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{leaf},
+			ServerName:       "scim.example.com",
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("SAN-bearing leaf with mismatched hostname is rejected", func(t *testing.T) {
+		leaf := sanLeaf(t, trustedCA, trustedKey, "scim.example.com")
+		verify := legacyCommonNameVerifier(roots, "legacy-appliance")
+		err := verify(tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{leaf},
+			ServerName:       "other.example.com",
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestMutualTLSOptFromEnvNoop(t *testing.T) {
+	t.Setenv("SCIM_CLIENT_CERT", "")
+	t.Setenv("SCIM_CLIENT_KEY", "")
+
+	opt, err := mutualTLSOptFromEnv()
+	assert.NoError(t, err)
+	assert.Nil(t, opt)
+}
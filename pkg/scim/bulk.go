@@ -0,0 +1,155 @@
+package scim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const bulkRequestSchema = "urn:ietf:params:scim:api:messages:2.0:BulkRequest"
+
+// bulkIDReferencePrefix marks a string within a bulk operation's Data as a
+// reference to another operation's bulkId, per RFC 7644 §3.7.2.1.
+const bulkIDReferencePrefix = "bulk:"
+
+// ErrBulkTooManyOperations is returned by Client.Bulk when a BulkRequest
+// carries more operations than the service provider's advertised
+// maxOperations limit.
+var ErrBulkTooManyOperations = errors.New("scim: bulk request exceeds the service provider's maxOperations limit")
+
+// ErrBulkPayloadTooLarge is returned by Client.Bulk when a BulkRequest's
+// marshaled payload exceeds the service provider's advertised
+// maxPayloadSize limit.
+var ErrBulkPayloadTooLarge = errors.New("scim: bulk request exceeds the service provider's maxPayloadSize limit")
+
+// BulkOperation is a single operation within a BulkRequest (RFC 7644
+// §3.7.2). Data may contain "bulk:<bulkId>" references to other
+// operations in the same request; the service provider resolves these
+// server-side, and BulkResponse.ResolveBulkID resolves them client-side
+// against the returned locations.
+type BulkOperation struct {
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	BulkID  string      `json:"bulkId,omitempty"`
+	Version string      `json:"version,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// BulkRequest is the RFC 7644 §3.7.2 bulk request envelope.
+type BulkRequest struct {
+	FailOnErrors int             `json:"failOnErrors,omitempty"`
+	Operations   []BulkOperation `json:"Operations"`
+}
+
+// BulkOperationResponse is a single operation's result within a
+// BulkResponse (RFC 7644 §3.7.3).
+type BulkOperationResponse struct {
+	Location string          `json:"location,omitempty"`
+	Method   string          `json:"method"`
+	BulkID   string          `json:"bulkId,omitempty"`
+	Version  string          `json:"version,omitempty"`
+	Status   string          `json:"status"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// Error decodes the operation's response as an ErrorResponse when its
+// status indicates failure, returning nil for a successful (2xx)
+// operation.
+func (r BulkOperationResponse) Error() error {
+	code, err := strconv.Atoi(r.Status)
+	if err != nil || code < 200 || code >= 300 {
+		var er ErrorResponse
+		if json.Unmarshal(r.Response, &er) == nil && len(er.Schemas) > 0 {
+			return er
+		}
+		return fmt.Errorf("scim: bulk operation %s %s failed with status %s", r.Method, r.BulkID, r.Status)
+	}
+	return nil
+}
+
+// BulkResponse is the RFC 7644 §3.7.3 bulk response envelope.
+type BulkResponse struct {
+	Operations []BulkOperationResponse `json:"Operations"`
+}
+
+// ResolveBulkID resolves a "bulk:<bulkId>" reference against a completed
+// BulkResponse, returning the location the service provider assigned to
+// the referenced operation.
+func (r BulkResponse) ResolveBulkID(ref string) (string, bool) {
+	id := strings.TrimPrefix(ref, bulkIDReferencePrefix)
+	for _, op := range r.Operations {
+		if op.BulkID == id {
+			return op.Location, true
+		}
+	}
+	return "", false
+}
+
+// Bulk submits ops to the service provider's /Bulk endpoint per RFC 7644
+// §3.7, rejecting requests that exceed the maxOperations/maxPayloadSize
+// limits discovered from ServiceProviderConfig before they hit the wire.
+func (c *Client) Bulk(ctx context.Context, br BulkRequest) (BulkResponse, error) {
+	spCfg, err := c.serviceProviderConfig(ctx)
+	if err != nil {
+		return BulkResponse{}, err
+	}
+	if spCfg.Bulk.MaxOperations > 0 && len(br.Operations) > spCfg.Bulk.MaxOperations {
+		return BulkResponse{}, ErrBulkTooManyOperations
+	}
+
+	payload := struct {
+		Schemas      []string        `json:"schemas"`
+		FailOnErrors int             `json:"failOnErrors,omitempty"`
+		Operations   []BulkOperation `json:"Operations"`
+	}{
+		Schemas:      []string{bulkRequestSchema},
+		FailOnErrors: br.FailOnErrors,
+		Operations:   br.Operations,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return BulkResponse{}, CodecError{Err: err.Error(), Op: Marshal}
+	}
+	if spCfg.Bulk.MaxPayloadSize > 0 && len(body) > spCfg.Bulk.MaxPayloadSize {
+		return BulkResponse{}, ErrBulkPayloadTooLarge
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.serviceURL()+"/Bulk", bytes.NewReader(body))
+	if err != nil {
+		return BulkResponse{}, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", scimContentType)
+	req.Header.Set("Content-Type", scimContentType)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return BulkResponse{}, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return BulkResponse{}, c.error(resp)
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return BulkResponse{}, err
+	}
+
+	var decoded struct {
+		Operations []BulkOperationResponse `json:"Operations"`
+	}
+	if err := json.Unmarshal(respBody, &decoded); err != nil {
+		return BulkResponse{}, CodecError{Err: err.Error(), Op: Unmarshal, Body: respBody}
+	}
+	return BulkResponse{Operations: decoded.Operations}, nil
+}
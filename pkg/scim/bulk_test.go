@@ -0,0 +1,153 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PennState/httputil/pkg/httptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulk(t *testing.T) {
+	const errorBody = `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:Error"],
+		"scimType":"invalidValue",
+		"detail":"Request size exceeds maxPayloadSize",
+		"status": "413"
+	}`
+
+	const partialResp = `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:BulkResponse"],
+		"Operations": [
+			{
+				"method": "POST",
+				"bulkId": "qwerty",
+				"version": "W\/\"1\"",
+				"location": "https://example.com/scim/Users/92b725cd",
+				"status": "201"
+			},
+			{
+				"method": "POST",
+				"bulkId": "asdfgh",
+				"status": "400",
+				"response": ` + errorBody + `
+			}
+		]
+	}`
+
+	tests := []struct {
+		name  string
+		br    BulkRequest
+		mock  httptest.MockTransport
+		spCfg *ServiceProviderConfig
+		exp   error
+		then  func(t *testing.T, resp BulkResponse)
+	}{
+		{
+			name: "Protocol error",
+			br:   BulkRequest{Operations: []BulkOperation{{Method: "POST", Path: "/Users"}}},
+			mock: httptest.MockTransport{
+				Req: &http.Request{Header: map[string][]string{}},
+				Err: errors.New("Protocol Error"),
+			},
+			exp: &url.Error{
+				Op:  "Post",
+				URL: "https://example.com/scim/Bulk",
+				Err: errors.New("Protocol Error"),
+			},
+		},
+		{
+			name: "Too many operations",
+			br: BulkRequest{Operations: []BulkOperation{
+				{Method: "POST", Path: "/Users"},
+				{Method: "POST", Path: "/Users"},
+				{Method: "POST", Path: "/Users"},
+			}},
+			mock: httptest.MockTransport{Req: &http.Request{Header: map[string][]string{}}},
+			exp:  ErrBulkTooManyOperations,
+		},
+		{
+			name: "413 Payload too large",
+			br:   BulkRequest{Operations: []BulkOperation{{Method: "POST", Path: "/Users"}}},
+			mock: httptest.MockTransport{
+				Req: &http.Request{Header: map[string][]string{}},
+				Resp: &http.Response{
+					StatusCode: 413,
+					Status:     "Request Entity Too Large",
+					Body:       ioutil.NopCloser(strings.NewReader(errorBody)),
+				},
+			},
+			exp: ErrorResponse{
+				Schemas:  []string{"urn:ietf:params:scim:api:messages:2.0:Error"},
+				ScimType: "invalidValue",
+				Detail:   "Request size exceeds maxPayloadSize",
+				Status:   "413",
+			},
+		},
+		{
+			name: "Payload too large before sending",
+			br: BulkRequest{Operations: []BulkOperation{
+				{Method: "POST", Path: "/Users", Data: map[string]string{"userName": "someone-with-a-rather-long-name@example.com"}},
+			}},
+			spCfg: &ServiceProviderConfig{
+				Bulk: BulkConfig{Supported: true, MaxOperations: 2, MaxPayloadSize: 10},
+			},
+			exp: ErrBulkPayloadTooLarge,
+		},
+		{
+			name: "Partial failure",
+			br: BulkRequest{Operations: []BulkOperation{
+				{Method: "POST", Path: "/Users", BulkID: "qwerty"},
+				{Method: "POST", Path: "/Users", BulkID: "asdfgh"},
+			}},
+			mock: httptest.MockTransport{
+				Req: &http.Request{Header: map[string][]string{}},
+				Resp: &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(strings.NewReader(partialResp)),
+				},
+			},
+			then: func(t *testing.T, resp BulkResponse) {
+				assert.Len(t, resp.Operations, 2)
+				assert.NoError(t, resp.Operations[0].Error())
+				assert.Error(t, resp.Operations[1].Error())
+				loc, ok := resp.ResolveBulkID("bulk:qwerty")
+				assert.True(t, ok)
+				assert.Equal(t, "https://example.com/scim/Users/92b725cd", loc)
+			},
+		},
+	}
+
+	for idx := range tests {
+		test := tests[idx]
+		t.Run(test.name, func(t *testing.T) {
+			test.mock.Req = &http.Request{URL: &url.URL{}, Header: map[string][]string{}}
+			spCfg := test.spCfg
+			if spCfg == nil {
+				spCfg = &ServiceProviderConfig{
+					Bulk: BulkConfig{Supported: true, MaxOperations: 2, MaxPayloadSize: 1 << 20},
+				}
+			}
+			cl := Client{
+				client: &client{
+					http:     &http.Client{Transport: test.mock},
+					cfg:      &clientCfg{ServiceURL: "https://example.com/scim"},
+					spConfig: spCfg,
+				},
+			}
+
+			resp, err := cl.Bulk(context.Background(), test.br)
+			if test.exp != nil {
+				assert.Equal(t, test.exp, err)
+				return
+			}
+			assert.NoError(t, err)
+			test.then(t, resp)
+		})
+	}
+}
@@ -0,0 +1,280 @@
+// Package scim implements a client for the System for Cross-domain
+// Identity Management protocol (SCIM 2.0, RFC 7643/7644).
+package scim
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PennState/httputil/pkg/httperror"
+	"golang.org/x/oauth2"
+)
+
+const scimContentType = "application/scim+json"
+
+const (
+	noServiceURLMessage      = "scim: a service URL must be provided"
+	invalidServiceURLMessage = "scim: the provided service URL is invalid"
+)
+
+// clientCfg holds the resolved configuration for a Client. It is fixed at
+// construction except for ServiceURL, which RedirectRewriteServiceURL may
+// rewrite later under client.cfgMu.
+type clientCfg struct {
+	ServiceURL       string
+	DisableDiscovery bool
+	DisableEtag      bool
+	IgnoreRedirects  bool
+
+	Redirect          redirectCfg
+	redirectPolicySet bool
+
+	Retry          RetryConfig
+	retryConfigSet bool
+
+	authMiddleware authMiddleware
+	baseTransport  http.RoundTripper
+}
+
+// ClientOpt configures a Client at construction time via NewClient.
+type ClientOpt func(*clientCfg) error
+
+// DisableDiscovery turns off the on-demand fetch of the SCIM service
+// provider's /ServiceProviderConfig endpoint; only a cached
+// ServiceProviderConfig is then used. /ResourceTypes is not fetched either
+// way.
+func DisableDiscovery(disable bool) ClientOpt {
+	return func(cfg *clientCfg) error {
+		cfg.DisableDiscovery = disable
+		return nil
+	}
+}
+
+// DisableEtag turns off the automatic use of a resource's ETag as an
+// If-Match precondition on mutating requests.
+func DisableEtag(disable bool) ClientOpt {
+	return func(cfg *clientCfg) error {
+		cfg.DisableEtag = disable
+		return nil
+	}
+}
+
+// IgnoreRedirects prevents the underlying http.Client from following
+// redirects returned by the SCIM service.
+func IgnoreRedirects(ignore bool) ClientOpt {
+	return func(cfg *clientCfg) error {
+		cfg.IgnoreRedirects = ignore
+		return nil
+	}
+}
+
+// client is the unexported, embeddable state shared by every Client value.
+type client struct {
+	http *http.Client
+
+	cfgMu sync.RWMutex
+	cfg   *clientCfg
+
+	spConfigMu sync.RWMutex
+	spConfig   *ServiceProviderConfig
+}
+
+// serviceURL returns the client's current SCIM service URL, safe for
+// concurrent use with an in-flight RedirectRewriteServiceURL update.
+func (c *client) serviceURL() string {
+	c.cfgMu.RLock()
+	defer c.cfgMu.RUnlock()
+	return c.cfg.ServiceURL
+}
+
+// Client is a SCIM 2.0 client bound to a single service provider.
+type Client struct {
+	*client
+}
+
+// NewClient builds a Client for the SCIM service at serviceURL. If
+// httpClient is nil, http.DefaultClient is used.
+func NewClient(httpClient *http.Client, serviceURL string, opts ...ClientOpt) (Client, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if serviceURL == "" {
+		return Client{}, errors.New(noServiceURLMessage)
+	}
+	u, err := url.Parse(serviceURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return Client{}, errors.New(invalidServiceURLMessage)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	cfg := &clientCfg{ServiceURL: u.String()}
+	for _, opt := range opts {
+		if err := opt(cfg); err != nil {
+			return Client{}, err
+		}
+	}
+
+	c := Client{
+		client: &client{
+			http: httpClient,
+			cfg:  cfg,
+		},
+	}
+	httpClient.CheckRedirect = c.checkRedirect()
+
+	transport := httpClient.Transport
+	if cfg.baseTransport != nil {
+		transport = cfg.baseTransport
+	}
+	if cfg.authMiddleware != nil {
+		transport = cfg.authMiddleware(transport)
+	}
+	if cfg.retryConfigSet {
+		transport = retryTransport{next: transport, cfg: cfg.Retry}
+	}
+	httpClient.Transport = transport
+
+	return c, nil
+}
+
+// NewClientFromEnv builds a Client using the SCIM_SERVICE_URL,
+// SCIM_IGNORE_REDIRECTS, SCIM_DISABLE_DISCOVERY, SCIM_DISABLE_ETAG,
+// SCIM_REDIRECT_POLICY, SCIM_BEARER_TOKEN, SCIM_CLIENT_CERT,
+// SCIM_CLIENT_KEY and SCIM_CA_BUNDLE environment variables.
+func NewClientFromEnv(httpClient *http.Client) (Client, error) {
+	var opts []ClientOpt
+
+	if ir, err := strconv.ParseBool(os.Getenv("SCIM_IGNORE_REDIRECTS")); err == nil {
+		opts = append(opts, IgnoreRedirects(ir))
+	}
+	if dd, err := strconv.ParseBool(os.Getenv("SCIM_DISABLE_DISCOVERY")); err == nil {
+		opts = append(opts, DisableDiscovery(dd))
+	}
+	if de, err := strconv.ParseBool(os.Getenv("SCIM_DISABLE_ETAG")); err == nil {
+		opts = append(opts, DisableEtag(de))
+	}
+	if rp, ok := redirectStrategyFromEnv(os.Getenv("SCIM_REDIRECT_POLICY")); ok {
+		opts = append(opts, RedirectPolicy(rp, 0))
+	}
+	if token := os.Getenv("SCIM_BEARER_TOKEN"); token != "" {
+		opts = append(opts, WithBearerToken(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})))
+	}
+	if mtlsOpt, err := mutualTLSOptFromEnv(); err != nil {
+		return Client{}, err
+	} else if mtlsOpt != nil {
+		opts = append(opts, mtlsOpt)
+	}
+
+	return NewClient(httpClient, os.Getenv("SCIM_SERVICE_URL"), opts...)
+}
+
+// newJSONBody marshals v into an io.Reader suitable for use as an
+// http.Request body, wrapping marshaling failures in a CodecError.
+func newJSONBody(v interface{}) (io.Reader, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, CodecError{
+			Err: err.Error(),
+			Op:  Marshal,
+		}
+	}
+	return bytes.NewReader(body), nil
+}
+
+// etag sets the If-Match header on req from res's current version, unless
+// ETag support has been disabled on the client.
+func (c *Client) etag(res Resource, req *http.Request) {
+	if c.cfg.DisableEtag {
+		return
+	}
+	vers := res.GetMeta().Version
+	if vers == "" {
+		return
+	}
+	req.Header.Add("If-Match", vers)
+}
+
+// error builds the error value to return for a non-2xx HTTP response,
+// decoding a SCIM ErrorResponse body when one is present.
+func (c *Client) error(resp *http.Response) error {
+	var body []byte
+	if resp.Body != nil {
+		body, _ = ioutil.ReadAll(resp.Body)
+	}
+
+	if len(body) > 0 {
+		var er ErrorResponse
+		if err := json.Unmarshal(body, &er); err == nil && len(er.Schemas) > 0 {
+			return er
+		}
+	}
+
+	return httperror.HTTPError{
+		Code:        resp.StatusCode,
+		Description: resp.Status,
+		Body:        string(body),
+	}
+}
+
+// resourceOrError executes req and decodes a successful response into res,
+// returning the appropriate error otherwise.
+func (c *Client) resourceOrError(res Resource, req *http.Request) error {
+	return c.decode(res, req, false)
+}
+
+// decode executes req and decodes a successful response into res. When
+// allowEmptyBody is true, a response with no body (e.g. a SCIM PATCH's 204
+// No Content) is treated as success rather than as an error, leaving res
+// unchanged.
+func (c *Client) decode(res Resource, req *http.Request, allowEmptyBody bool) error {
+	req.Header.Set("Accept", scimContentType)
+	req.Header.Set("Content-Type", scimContentType)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return c.error(resp)
+	}
+
+	if resp.Body == nil {
+		if allowEmptyBody {
+			return nil
+		}
+		return errors.New("<No body>")
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		if allowEmptyBody {
+			return nil
+		}
+		return errors.New("<No body>")
+	}
+
+	if err := json.Unmarshal(body, res); err != nil {
+		return CodecError{
+			Err:  err.Error(),
+			Op:   Unmarshal,
+			Body: body,
+		}
+	}
+	return nil
+}
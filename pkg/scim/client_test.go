@@ -1,15 +1,23 @@
 package scim
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"math"
+	"math/big"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/PennState/httputil/pkg/httperror"
 	"github.com/PennState/httputil/pkg/httptest"
@@ -47,12 +55,59 @@ func TestNewClientFromEnv(t *testing.T) {
 	os.Setenv("SCIM_IGNORE_REDIRECTS", "true")
 	os.Setenv("SCIM_DISABLE_DISCOVERY", "true")
 	os.Setenv("SCIM_DISABLE_ETAG", "true")
+	os.Setenv("SCIM_REDIRECT_POLICY", "reject")
+	os.Setenv("SCIM_BEARER_TOKEN", "s3cr3t-token")
 	c, err := NewClientFromEnv(nil)
 	assert.NoError(t, err)
 	assert.Equal(t, c.cfg.ServiceURL, url)
 	assert.True(t, c.cfg.IgnoreRedirects)
 	assert.True(t, c.cfg.DisableDiscovery)
 	assert.True(t, c.cfg.DisableEtag)
+	assert.True(t, c.cfg.redirectPolicySet)
+	assert.NotNil(t, c.cfg.authMiddleware)
+	assert.Equal(t, RedirectReject, c.cfg.Redirect.Strategy)
+}
+
+func TestNewClientFromEnvMutualTLS(t *testing.T) {
+	ca, caKey := selfSignedCA(t)
+
+	leafKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	leafTmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "scim-client"},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(100 * 365 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTmpl, ca, &leafKey.PublicKey, caKey)
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+
+	certFile := filepath.Join(dir, "client.crt")
+	assert.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER}), 0o600))
+
+	keyFile := filepath.Join(dir, "client.key")
+	assert.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(leafKey)}), 0o600))
+
+	caFile := filepath.Join(dir, "ca.crt")
+	assert.NoError(t, ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0o600))
+
+	os.Setenv("SCIM_SERVICE_URL", "https://example.com/scim")
+	os.Unsetenv("SCIM_BEARER_TOKEN")
+	os.Setenv("SCIM_CLIENT_CERT", certFile)
+	os.Setenv("SCIM_CLIENT_KEY", keyFile)
+	os.Setenv("SCIM_CA_BUNDLE", caFile)
+	defer func() {
+		os.Unsetenv("SCIM_CLIENT_CERT")
+		os.Unsetenv("SCIM_CLIENT_KEY")
+		os.Unsetenv("SCIM_CA_BUNDLE")
+	}()
+
+	c, err := NewClientFromEnv(nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, c.cfg.baseTransport)
 }
 
 func TestServiceURLParsing(t *testing.T) {
@@ -0,0 +1,37 @@
+package scim
+
+import "fmt"
+
+// Op identifies which direction of JSON (de)serialization a CodecError
+// occurred during.
+type Op int
+
+// The codec operations a CodecError can be attributed to.
+const (
+	Marshal Op = iota
+	Unmarshal
+)
+
+func (o Op) String() string {
+	switch o {
+	case Marshal:
+		return "marshal"
+	case Unmarshal:
+		return "unmarshal"
+	default:
+		return "unknown"
+	}
+}
+
+// CodecError wraps a JSON (de)serialization failure together with the
+// offending body so that callers can log or inspect the raw bytes that
+// the client couldn't make sense of.
+type CodecError struct {
+	Err  string
+	Op   Op
+	Body []byte
+}
+
+func (e CodecError) Error() string {
+	return fmt.Sprintf("scim: %s error: %s", e.Op, e.Err)
+}
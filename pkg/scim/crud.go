@@ -0,0 +1,69 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+)
+
+// Get retrieves the resource of the given type and id, decoding the
+// response into res.
+func (c *Client) Get(ctx context.Context, resourceType, id string, res Resource) error {
+	req, err := http.NewRequest(http.MethodGet, c.serviceURL()+"/"+resourceType+"/"+id, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	return c.resourceOrError(res, req)
+}
+
+// Create POSTs res to the given resource type's endpoint, replacing res
+// in-place with the server's representation of the created resource.
+func (c *Client) Create(ctx context.Context, resourceType string, res Resource) error {
+	body, err := newJSONBody(res)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, c.serviceURL()+"/"+resourceType, body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	return c.resourceOrError(res, req)
+}
+
+// Replace PUTs res to the given resource type's endpoint at id, replacing
+// res in-place with the server's representation of the updated resource.
+func (c *Client) Replace(ctx context.Context, resourceType, id string, res Resource) error {
+	body, err := newJSONBody(res)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, c.serviceURL()+"/"+resourceType+"/"+id, body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	c.etag(res, req)
+	return c.resourceOrError(res, req)
+}
+
+// Delete removes the resource of the given type and id.
+func (c *Client) Delete(ctx context.Context, resourceType, id string) error {
+	req, err := http.NewRequest(http.MethodDelete, c.serviceURL()+"/"+resourceType+"/"+id, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return c.error(resp)
+	}
+	return nil
+}
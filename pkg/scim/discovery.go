@@ -0,0 +1,82 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// ErrDiscoveryDisabled is returned by serviceProviderConfig when the
+// DisableDiscovery ClientOpt is set and no ServiceProviderConfig has been
+// cached yet, rather than silently falling back to a live fetch.
+var ErrDiscoveryDisabled = errors.New("scim: automatic discovery is disabled and no ServiceProviderConfig is cached")
+
+// BulkConfig describes the service provider's support for and limits on
+// bulk operations, as returned in the "bulk" section of
+// ServiceProviderConfig (RFC 7644 §5, §3.7).
+type BulkConfig struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+// PatchConfig describes the service provider's support for PATCH
+// (RFC 7644 §5, §3.5.2).
+type PatchConfig struct {
+	Supported bool `json:"supported"`
+}
+
+// FilterConfig describes the service provider's support for filtering
+// (RFC 7644 §5, §3.4.2.2).
+type FilterConfig struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+// ServiceProviderConfig is the RFC 7644 §5 resource that advertises a SCIM
+// service provider's supported features and limits.
+type ServiceProviderConfig struct {
+	CommonAttributes
+	DocumentationURI string       `json:"documentationUri,omitempty"`
+	Patch            PatchConfig  `json:"patch"`
+	Bulk             BulkConfig   `json:"bulk"`
+	Filter           FilterConfig `json:"filter"`
+}
+
+// discover fetches and caches the service provider's ServiceProviderConfig.
+// It is safe to call repeatedly, and is re-run whenever the client's
+// ServiceURL changes (e.g. RedirectRewriteServiceURL).
+func (c *Client) discover(ctx context.Context) (ServiceProviderConfig, error) {
+	req, err := http.NewRequest(http.MethodGet, c.serviceURL()+"/ServiceProviderConfig", nil)
+	if err != nil {
+		return ServiceProviderConfig{}, err
+	}
+	req = req.WithContext(ctx)
+
+	var spCfg ServiceProviderConfig
+	if err := c.resourceOrError(&spCfg, req); err != nil {
+		return ServiceProviderConfig{}, err
+	}
+
+	c.spConfigMu.Lock()
+	c.spConfig = &spCfg
+	c.spConfigMu.Unlock()
+
+	return spCfg, nil
+}
+
+// serviceProviderConfig returns the cached ServiceProviderConfig, fetching
+// it first if discovery hasn't run yet. When DisableDiscovery is set and
+// nothing is cached, it returns ErrDiscoveryDisabled instead of fetching.
+func (c *Client) serviceProviderConfig(ctx context.Context) (ServiceProviderConfig, error) {
+	c.spConfigMu.RLock()
+	cached := c.spConfig
+	c.spConfigMu.RUnlock()
+	if cached != nil {
+		return *cached, nil
+	}
+	if c.cfg.DisableDiscovery {
+		return ServiceProviderConfig{}, ErrDiscoveryDisabled
+	}
+	return c.discover(ctx)
+}
@@ -0,0 +1,34 @@
+package scim
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceProviderConfigDiscoveryDisabled(t *testing.T) {
+	cl := Client{
+		client: &client{
+			cfg: &clientCfg{ServiceURL: "https://example.com/scim", DisableDiscovery: true},
+		},
+	}
+
+	spCfg, err := cl.serviceProviderConfig(context.Background())
+	assert.Equal(t, ErrDiscoveryDisabled, err)
+	assert.Equal(t, ServiceProviderConfig{}, spCfg)
+}
+
+func TestServiceProviderConfigDiscoveryDisabledUsesCache(t *testing.T) {
+	cached := &ServiceProviderConfig{DocumentationURI: "https://example.com/docs"}
+	cl := Client{
+		client: &client{
+			cfg:      &clientCfg{ServiceURL: "https://example.com/scim", DisableDiscovery: true},
+			spConfig: cached,
+		},
+	}
+
+	spCfg, err := cl.serviceProviderConfig(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, *cached, spCfg)
+}
@@ -0,0 +1,19 @@
+package scim
+
+import "fmt"
+
+// ErrorResponse is the SCIM protocol error representation returned by
+// servers in response bodies, as defined in RFC 7644 §3.12.
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+	Status   string   `json:"status"`
+}
+
+func (e ErrorResponse) Error() string {
+	if e.ScimType != "" {
+		return fmt.Sprintf("scim: %s: %s (status %s)", e.ScimType, e.Detail, e.Status)
+	}
+	return fmt.Sprintf("scim: %s (status %s)", e.Detail, e.Status)
+}
@@ -0,0 +1,155 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// ErrPageDrift is returned by ResourceIterator.Err when a server's
+// totalResults changes mid-scroll, indicating the result set was mutated
+// while the iterator was paging through it.
+var ErrPageDrift = errors.New("scim: totalResults changed mid-scroll")
+
+const defaultIteratorPrefetch = 1
+
+type iterCfg struct {
+	prefetch int
+}
+
+// IteratorOpt configures a ResourceIterator returned by Client.Iterate.
+type IteratorOpt func(*iterCfg)
+
+// IteratorPrefetch sets the number of resources the iterator will fetch
+// ahead of the caller's Scan calls, buffered on an internal channel.
+func IteratorPrefetch(n int) IteratorOpt {
+	return func(cfg *iterCfg) {
+		cfg.prefetch = n
+	}
+}
+
+type iterResult struct {
+	raw json.RawMessage
+	err error
+}
+
+// ResourceIterator is a cursor over a paged SCIM list response, prefetching
+// the next page in the background while the caller scans the current one.
+type ResourceIterator struct {
+	cancel context.CancelFunc
+	ch     chan iterResult
+	cur    iterResult
+	err    error
+}
+
+// Iterate pages through resourceType using sr, issuing startIndex/count
+// requests as needed and prefetching the next page in a goroutine while
+// the caller scans the current one.
+func (c *Client) Iterate(ctx context.Context, resourceType string, sr SearchRequest, opts ...IteratorOpt) *ResourceIterator {
+	cfg := iterCfg{prefetch: defaultIteratorPrefetch}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	it := &ResourceIterator{
+		cancel: cancel,
+		ch:     make(chan iterResult, cfg.prefetch),
+	}
+	go it.run(ctx, c, resourceType, sr)
+	return it
+}
+
+func (it *ResourceIterator) run(ctx context.Context, c *Client, resourceType string, sr SearchRequest) {
+	defer close(it.ch)
+
+	startIndex := sr.StartIndex
+	if startIndex <= 0 {
+		startIndex = 1
+	}
+	count := sr.Count
+	if count <= 0 {
+		count = 100
+	}
+
+	seenTotal := -1
+	fetched := 0
+	for {
+		page := sr
+		page.StartIndex = startIndex
+		page.Count = count
+
+		lr, err := c.ListResources(ctx, resourceType, page)
+		if err != nil {
+			it.emit(ctx, iterResult{err: err})
+			return
+		}
+
+		if seenTotal == -1 {
+			seenTotal = lr.TotalResults
+		} else if lr.TotalResults != seenTotal {
+			it.emit(ctx, iterResult{err: ErrPageDrift})
+			return
+		}
+
+		for _, raw := range lr.Resources {
+			if !it.emit(ctx, iterResult{raw: raw}) {
+				return
+			}
+		}
+
+		fetched += len(lr.Resources)
+		if len(lr.Resources) == 0 || fetched >= lr.TotalResults {
+			return
+		}
+		startIndex += len(lr.Resources)
+	}
+}
+
+// emit delivers a result to the caller, returning false if the iterator
+// was closed first.
+func (it *ResourceIterator) emit(ctx context.Context, res iterResult) bool {
+	select {
+	case it.ch <- res:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Next advances the iterator, returning false once the result set (or an
+// error) is exhausted. Callers should check Err after Next returns false.
+func (it *ResourceIterator) Next() bool {
+	res, ok := <-it.ch
+	if !ok {
+		return false
+	}
+	it.cur = res
+	if res.err != nil {
+		it.err = res.err
+		return false
+	}
+	return true
+}
+
+// Scan decodes the current resource into v.
+func (it *ResourceIterator) Scan(v interface{}) error {
+	return json.Unmarshal(it.cur.raw, v)
+}
+
+// Err returns the first error encountered while paging, including
+// ErrPageDrift, or nil if the iterator was exhausted cleanly.
+func (it *ResourceIterator) Err() error {
+	return it.err
+}
+
+// Close stops the background prefetch goroutine. Every page fetched by
+// Iterate already has its HTTP response body fully read and closed by
+// ListResources/resourceOrError, so draining the result channel here is
+// enough to let an abandoned iterator's goroutine exit without leaking
+// the underlying http.Client's pooled connections.
+func (it *ResourceIterator) Close() {
+	it.cancel()
+	for range it.ch {
+	}
+}
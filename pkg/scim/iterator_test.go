@@ -0,0 +1,94 @@
+package scim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newPagingServer(t *testing.T, total int, pageSize int, drift bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := 1
+		if s := r.URL.Query().Get("startIndex"); s != "" {
+			fmt.Sscanf(s, "%d", &start)
+		}
+
+		reportedTotal := total
+		if drift && start > 1 {
+			reportedTotal = total + 1
+		}
+
+		end := start + pageSize - 1
+		if end > total {
+			end = total
+		}
+
+		w.Header().Set("Content-Type", scimContentType)
+		fmt.Fprintf(w, `{"schemas":["urn:ietf:params:scim:api:messages:2.0:ListResponse"],"totalResults":%d,"Resources":[`, reportedTotal)
+		for i := start; i <= end; i++ {
+			if i > start {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"id":"%d","userName":"user%d"}`, i, i)
+		}
+		fmt.Fprint(w, `]}`)
+	}))
+}
+
+func TestIterate(t *testing.T) {
+	srv := newPagingServer(t, 5, 2, false)
+	defer srv.Close()
+
+	c := Client{client: &client{http: srv.Client(), cfg: &clientCfg{ServiceURL: srv.URL}}}
+
+	it := c.Iterate(context.Background(), "Users", SearchRequest{Count: 2})
+	defer it.Close()
+
+	var ids []string
+	for it.Next() {
+		u := User{}
+		assert.NoError(t, it.Scan(&u))
+		ids = append(ids, u.ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, ids)
+}
+
+func TestIterateServerReturnsFewerThanRequested(t *testing.T) {
+	srv := newPagingServer(t, 10, 3, false)
+	defer srv.Close()
+
+	c := Client{client: &client{http: srv.Client(), cfg: &clientCfg{ServiceURL: srv.URL}}}
+
+	it := c.Iterate(context.Background(), "Users", SearchRequest{Count: 5})
+	defer it.Close()
+
+	var ids []string
+	for it.Next() {
+		u := User{}
+		assert.NoError(t, it.Scan(&u))
+		ids = append(ids, u.ID)
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}, ids)
+}
+
+func TestIteratePageDrift(t *testing.T) {
+	srv := newPagingServer(t, 5, 2, true)
+	defer srv.Close()
+
+	c := Client{client: &client{http: srv.Client(), cfg: &clientCfg{ServiceURL: srv.URL}}}
+
+	it := c.Iterate(context.Background(), "Users", SearchRequest{Count: 2})
+	defer it.Close()
+
+	for it.Next() {
+		var u User
+		assert.NoError(t, it.Scan(&u))
+	}
+	assert.Equal(t, ErrPageDrift, it.Err())
+}
@@ -0,0 +1,123 @@
+package scim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+const patchOpSchema = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+
+// PatchOperation is a single RFC 6902-style operation within a SCIM PATCH
+// request (RFC 7644 §3.5.2).
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// scimPathPattern is a conservative approximation of the SCIM path grammar
+// (RFC 7644 §3.5.2, Appendix B): an attribute name, optionally prefixed by
+// a schema URI (e.g. the Enterprise User extension's
+// "urn:...:User:"), and optionally sub-attributed and/or filtered with a
+// bracketed valuePath expression.
+var scimPathPattern = regexp.MustCompile(`^(?:[\w.:\/-]+:)?[A-Za-z][\w-]*(\[[^\]]+\])?(\.[A-Za-z][\w-]*)?$`)
+
+func validatePath(path string) error {
+	if path == "" {
+		return nil
+	}
+	if !scimPathPattern.MatchString(path) {
+		return ErrorResponse{
+			Schemas:  []string{"urn:ietf:params:scim:api:messages:2.0:Error"},
+			ScimType: "invalidPath",
+			Detail:   fmt.Sprintf("%q is not a valid SCIM attribute path", path),
+			Status:   "400",
+		}
+	}
+	return nil
+}
+
+// PatchBuilder builds a slice of PatchOperations through a fluent API,
+// validating each path against the SCIM path grammar as it's added.
+type PatchBuilder struct {
+	ops []PatchOperation
+	err error
+}
+
+// NewPatchBuilder starts an empty PatchBuilder.
+func NewPatchBuilder() *PatchBuilder {
+	return &PatchBuilder{}
+}
+
+func (b *PatchBuilder) add(op, path string, value interface{}) *PatchBuilder {
+	if b.err != nil {
+		return b
+	}
+	if err := validatePath(path); err != nil {
+		b.err = err
+		return b
+	}
+	b.ops = append(b.ops, PatchOperation{Op: op, Path: path, Value: value})
+	return b
+}
+
+// Add appends an "add" operation for path.
+func (b *PatchBuilder) Add(path string, value interface{}) *PatchBuilder {
+	return b.add("add", path, value)
+}
+
+// Replace appends a "replace" operation for path.
+func (b *PatchBuilder) Replace(path string, value interface{}) *PatchBuilder {
+	return b.add("replace", path, value)
+}
+
+// Remove appends a "remove" operation for path.
+func (b *PatchBuilder) Remove(path string) *PatchBuilder {
+	return b.add("remove", path, nil)
+}
+
+// Build returns the accumulated operations, or the first path validation
+// error encountered while building them.
+func (b *PatchBuilder) Build() ([]PatchOperation, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.ops, nil
+}
+
+// Patch applies ops to res via SCIM PATCH (RFC 7644 §3.5.2), sending an
+// If-Match precondition from res's current ETag and updating res in place
+// with whatever the server returns (a 200 body, or nothing on 204).
+func (c *Client) Patch(ctx context.Context, res Resource, ops []PatchOperation) error {
+	for _, op := range ops {
+		if err := validatePath(op.Path); err != nil {
+			return err
+		}
+	}
+
+	payload := struct {
+		Schemas    []string         `json:"schemas"`
+		Operations []PatchOperation `json:"Operations"`
+	}{
+		Schemas:    []string{patchOpSchema},
+		Operations: ops,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return CodecError{Err: err.Error(), Op: Marshal}
+	}
+
+	loc := res.GetMeta().Location
+	req, err := http.NewRequest(http.MethodPatch, loc, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	c.etag(res, req)
+
+	return c.decode(res, req, true)
+}
@@ -0,0 +1,136 @@
+package scim
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/PennState/httputil/pkg/httptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchBuilder(t *testing.T) {
+	t.Run("Valid chain", func(t *testing.T) {
+		ops, err := NewPatchBuilder().
+			Replace("name.familyName", "Smith").
+			Add("emails", map[string]string{"value": "new@example.com"}).
+			Remove(`emails[type eq "work"]`).
+			Build()
+		assert.NoError(t, err)
+		assert.Len(t, ops, 3)
+		assert.Equal(t, "replace", ops[0].Op)
+		assert.Equal(t, "add", ops[1].Op)
+		assert.Equal(t, "remove", ops[2].Op)
+	})
+
+	t.Run("Extension schema path", func(t *testing.T) {
+		ops, err := NewPatchBuilder().
+			Replace("urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department", "Sales").
+			Build()
+		assert.NoError(t, err)
+		assert.Len(t, ops, 1)
+	})
+
+	t.Run("Invalid path", func(t *testing.T) {
+		_, err := NewPatchBuilder().Replace("not a path!!", "x").Build()
+		assert.Error(t, err)
+		er, ok := err.(ErrorResponse)
+		assert.True(t, ok)
+		assert.Equal(t, "invalidPath", er.ScimType)
+	})
+}
+
+func TestPatch(t *testing.T) {
+	const invalidValueBody = `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:Error"],
+		"scimType":"invalidValue",
+		"detail":"Value is not a valid email",
+		"status": "400"
+	}`
+
+	const replacedUser = `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"id": "2819c223-7f76-453a-919d-413861904646",
+		"userName": "bjensen@example.com",
+		"meta": {"version": "W\/\"2\""}
+	}`
+
+	tests := []struct {
+		name string
+		mock httptest.MockTransport
+		exp  error
+	}{
+		{
+			name: "204 No Content",
+			mock: httptest.MockTransport{
+				Req:  &http.Request{Header: map[string][]string{}},
+				Resp: &http.Response{StatusCode: 204},
+			},
+		},
+		{
+			name: "200 with returned resource",
+			mock: httptest.MockTransport{
+				Req: &http.Request{Header: map[string][]string{}},
+				Resp: &http.Response{
+					StatusCode: 200,
+					Body:       ioutil.NopCloser(strings.NewReader(replacedUser)),
+				},
+			},
+		},
+		{
+			name: "invalidValue from server",
+			mock: httptest.MockTransport{
+				Req: &http.Request{Header: map[string][]string{}},
+				Resp: &http.Response{
+					StatusCode: 400,
+					Status:     "Bad request",
+					Body:       ioutil.NopCloser(strings.NewReader(invalidValueBody)),
+				},
+			},
+			exp: ErrorResponse{
+				Schemas:  []string{"urn:ietf:params:scim:api:messages:2.0:Error"},
+				ScimType: "invalidValue",
+				Detail:   "Value is not a valid email",
+				Status:   "400",
+			},
+		},
+	}
+
+	for idx := range tests {
+		test := tests[idx]
+		t.Run(test.name, func(t *testing.T) {
+			test.mock.Req = &http.Request{URL: &url.URL{}, Header: map[string][]string{}}
+			cl := Client{client: &client{http: &http.Client{Transport: test.mock}, cfg: &clientCfg{}}}
+
+			user := User{
+				CommonAttributes: CommonAttributes{Meta: Meta{Location: "https://example.com/scim/Users/2819c223"}},
+			}
+			ops, err := NewPatchBuilder().Replace("name.familyName", "Smith").Build()
+			assert.NoError(t, err)
+
+			act := cl.Patch(context.Background(), &user, ops)
+			if test.exp != nil {
+				assert.Equal(t, test.exp, act)
+				return
+			}
+			assert.NoError(t, act)
+		})
+	}
+}
+
+func TestPatchRejectsInvalidPathBeforeSending(t *testing.T) {
+	cl := Client{client: &client{http: &http.Client{Transport: httptest.MockTransport{
+		Req: &http.Request{URL: &url.URL{}, Header: map[string][]string{}},
+		Err: assert.AnError,
+	}}}}
+
+	user := User{CommonAttributes: CommonAttributes{Meta: Meta{Location: "https://example.com/scim/Users/1"}}}
+	err := cl.Patch(context.Background(), &user, []PatchOperation{{Op: "replace", Path: "not a path!!", Value: "x"}})
+
+	er, ok := err.(ErrorResponse)
+	assert.True(t, ok)
+	assert.Equal(t, "invalidPath", er.ScimType)
+}
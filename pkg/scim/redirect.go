@@ -0,0 +1,162 @@
+package scim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RedirectStrategy selects how a Client reacts to HTTP redirects returned
+// by the SCIM service, via the RedirectPolicy ClientOpt.
+type RedirectStrategy int
+
+const (
+	// RedirectFollow follows redirects unconditionally, same as
+	// net/http's default behavior.
+	RedirectFollow RedirectStrategy = iota
+	// RedirectFollowSameHost follows a redirect only when its Location
+	// stays on the host the request was originally sent to.
+	RedirectFollowSameHost
+	// RedirectReject refuses to follow any redirect, returning the
+	// redirect response itself to the caller (equivalent to the legacy
+	// IgnoreRedirects(true)).
+	RedirectReject
+	// RedirectRewriteServiceURL treats a 301 or 308 redirect from a
+	// discovery endpoint (/ServiceProviderConfig, /ResourceTypes,
+	// /Schemas) as a signal that the service provider has permanently
+	// moved its SCIM base URL: the Client updates cfg.ServiceURL in
+	// place, re-runs discovery, and does not follow the redirect itself.
+	// Other redirect statuses (302, 303, 307) are left to fall through
+	// to the default follow behavior, since they may be transient.
+	RedirectRewriteServiceURL
+)
+
+const defaultMaxRedirects = 10
+
+// discoveryPathSuffixes lists the endpoints whose redirects
+// RedirectRewriteServiceURL treats as a service URL migration.
+var discoveryPathSuffixes = []string{"/ServiceProviderConfig", "/ResourceTypes", "/Schemas"}
+
+type redirectCfg struct {
+	Strategy RedirectStrategy
+	MaxHops  int
+}
+
+// RedirectPolicy configures how the Client follows HTTP redirects. When
+// supplied, it supersedes the coarser IgnoreRedirects option. maxHops
+// bounds the number of redirects followed before giving up; 0 selects the
+// net/http default of 10.
+func RedirectPolicy(strategy RedirectStrategy, maxHops int) ClientOpt {
+	return func(cfg *clientCfg) error {
+		cfg.Redirect = redirectCfg{Strategy: strategy, MaxHops: maxHops}
+		cfg.redirectPolicySet = true
+		return nil
+	}
+}
+
+// resolvedRedirectCfg reconciles the RedirectPolicy option with the
+// legacy IgnoreRedirects boolean, preferring the former when both are set.
+func (cfg *clientCfg) resolvedRedirectCfg() redirectCfg {
+	if cfg.redirectPolicySet {
+		return cfg.Redirect
+	}
+	if cfg.IgnoreRedirects {
+		return redirectCfg{Strategy: RedirectReject}
+	}
+	return redirectCfg{Strategy: RedirectFollow}
+}
+
+// checkRedirect builds the http.Client.CheckRedirect func implementing
+// the client's resolved redirect policy. Go's http.Client already
+// preserves method and body across 307/308 redirects, so SCIM semantics
+// are maintained without any extra handling here.
+func (c *Client) checkRedirect() func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		redirect := c.cfg.resolvedRedirectCfg()
+
+		maxHops := redirect.MaxHops
+		if maxHops == 0 {
+			maxHops = defaultMaxRedirects
+		}
+		if len(via) >= maxHops {
+			return fmt.Errorf("scim: stopped after %d redirects", maxHops)
+		}
+
+		switch redirect.Strategy {
+		case RedirectReject:
+			return http.ErrUseLastResponse
+		case RedirectFollowSameHost:
+			if req.URL.Host != via[0].URL.Host {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		case RedirectRewriteServiceURL:
+			last := via[len(via)-1]
+			if isDiscoveryPath(last.URL.Path) && isPermanentRedirect(last) {
+				c.rewriteServiceURL(req.URL.String())
+				return http.ErrUseLastResponse
+			}
+			return nil
+		default:
+			return nil
+		}
+	}
+}
+
+// redirectStrategyFromEnv maps the SCIM_REDIRECT_POLICY environment
+// variable's value ("follow", "follow-same-host", "reject",
+// "rewrite-service-url") to a RedirectStrategy.
+func redirectStrategyFromEnv(val string) (RedirectStrategy, bool) {
+	switch val {
+	case "follow":
+		return RedirectFollow, true
+	case "follow-same-host":
+		return RedirectFollowSameHost, true
+	case "reject":
+		return RedirectReject, true
+	case "rewrite-service-url":
+		return RedirectRewriteServiceURL, true
+	default:
+		return RedirectFollow, false
+	}
+}
+
+// isPermanentRedirect reports whether req's triggering response (populated
+// by net/http on each hop of a client redirect) was a 301 or 308, the only
+// statuses RedirectRewriteServiceURL treats as a genuine service URL
+// migration. Transient 302/303/307 redirects are left alone.
+func isPermanentRedirect(req *http.Request) bool {
+	return req.Response != nil &&
+		(req.Response.StatusCode == http.StatusMovedPermanently ||
+			req.Response.StatusCode == http.StatusPermanentRedirect)
+}
+
+func isDiscoveryPath(path string) bool {
+	for _, suffix := range discoveryPathSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteServiceURL updates cfg.ServiceURL to the server's new location,
+// with any trailing discovery endpoint stripped so that only the base URL
+// remains, and re-runs discovery against it, so that the rest of the
+// Client picks up the migrated base URL on its next request.
+func (c *Client) rewriteServiceURL(newURL string) {
+	newURL = strings.TrimSuffix(newURL, "/")
+	for _, suffix := range discoveryPathSuffixes {
+		if strings.HasSuffix(newURL, suffix) {
+			newURL = strings.TrimSuffix(newURL, suffix)
+			break
+		}
+	}
+
+	c.cfgMu.Lock()
+	c.cfg.ServiceURL = newURL
+	c.cfgMu.Unlock()
+
+	go c.discover(context.Background())
+}
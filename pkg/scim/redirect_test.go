@@ -0,0 +1,136 @@
+package scim
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/PennState/httputil/pkg/httptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     clientCfg
+		req     *http.Request
+		via     []*http.Request
+		expErr  bool
+		expSame bool
+	}{
+		{
+			name:   "Reject",
+			cfg:    clientCfg{Redirect: redirectCfg{Strategy: RedirectReject}, redirectPolicySet: true},
+			req:    &http.Request{URL: &url.URL{Host: "other.example.com"}},
+			via:    []*http.Request{{URL: &url.URL{Host: "example.com"}}},
+			expErr: true,
+		},
+		{
+			name:    "FollowSameHost - same host",
+			cfg:     clientCfg{Redirect: redirectCfg{Strategy: RedirectFollowSameHost}, redirectPolicySet: true},
+			req:     &http.Request{URL: &url.URL{Host: "example.com"}},
+			via:     []*http.Request{{URL: &url.URL{Host: "example.com"}}},
+			expSame: true,
+		},
+		{
+			name:   "FollowSameHost - different host",
+			cfg:    clientCfg{Redirect: redirectCfg{Strategy: RedirectFollowSameHost}, redirectPolicySet: true},
+			req:    &http.Request{URL: &url.URL{Host: "other.example.com"}},
+			via:    []*http.Request{{URL: &url.URL{Host: "example.com"}}},
+			expErr: true,
+		},
+		{
+			name:   "Legacy IgnoreRedirects true behaves like Reject",
+			cfg:    clientCfg{IgnoreRedirects: true},
+			req:    &http.Request{URL: &url.URL{Host: "example.com"}},
+			via:    []*http.Request{{URL: &url.URL{Host: "example.com"}}},
+			expErr: true,
+		},
+		{
+			name:   "Too many hops",
+			cfg:    clientCfg{Redirect: redirectCfg{Strategy: RedirectFollow, MaxHops: 1}, redirectPolicySet: true},
+			req:    &http.Request{URL: &url.URL{Host: "example.com"}},
+			via:    []*http.Request{{URL: &url.URL{Host: "example.com"}}},
+			expErr: true,
+		},
+	}
+
+	for idx := range tests {
+		test := tests[idx]
+		t.Run(test.name, func(t *testing.T) {
+			c := Client{client: &client{cfg: &test.cfg}}
+			err := c.checkRedirect()(test.req, test.via)
+			if test.expErr {
+				assert.Error(t, err)
+				return
+			}
+			if test.expSame {
+				assert.NoError(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRedirectRewriteServiceURL(t *testing.T) {
+	discoveryReq := func(status int) *http.Request {
+		return &http.Request{
+			URL:      &url.URL{Scheme: "https", Host: "example.com", Path: "/scim/ServiceProviderConfig"},
+			Response: &http.Response{StatusCode: status},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		via       []*http.Request
+		expRewrit bool
+	}{
+		{name: "301 rewrites", via: []*http.Request{discoveryReq(http.StatusMovedPermanently)}, expRewrit: true},
+		{name: "308 rewrites", via: []*http.Request{discoveryReq(http.StatusPermanentRedirect)}, expRewrit: true},
+		{name: "302 does not rewrite", via: []*http.Request{discoveryReq(http.StatusFound)}, expRewrit: false},
+		{name: "307 does not rewrite", via: []*http.Request{discoveryReq(http.StatusTemporaryRedirect)}, expRewrit: false},
+	}
+
+	for idx := range tests {
+		test := tests[idx]
+		t.Run(test.name, func(t *testing.T) {
+			cfg := clientCfg{
+				ServiceURL:        "https://example.com/scim",
+				Redirect:          redirectCfg{Strategy: RedirectRewriteServiceURL},
+				redirectPolicySet: true,
+			}
+			// rewriteServiceURL re-runs discovery in a goroutine; give the
+			// client a transport that fails fast instead of nil-panicking.
+			mock := httptest.MockTransport{
+				Req: &http.Request{Header: map[string][]string{}},
+				Err: errors.New("discovery unreachable in test"),
+			}
+			c := Client{client: &client{cfg: &cfg, http: &http.Client{Transport: mock}}}
+			req := &http.Request{URL: &url.URL{Scheme: "https", Host: "new.example.com", Path: "/scim2/ServiceProviderConfig"}}
+
+			err := c.checkRedirect()(req, test.via)
+
+			if test.expRewrit {
+				assert.Equal(t, http.ErrUseLastResponse, err)
+				c.cfgMu.RLock()
+				gotURL := c.cfg.ServiceURL
+				c.cfgMu.RUnlock()
+				assert.Equal(t, "https://new.example.com/scim2", gotURL)
+			} else {
+				assert.NoError(t, err)
+				c.cfgMu.RLock()
+				gotURL := c.cfg.ServiceURL
+				c.cfgMu.RUnlock()
+				assert.Equal(t, "https://example.com/scim", gotURL)
+			}
+		})
+	}
+}
+
+func TestIsDiscoveryPath(t *testing.T) {
+	assert.True(t, isDiscoveryPath("/scim/ServiceProviderConfig"))
+	assert.True(t, isDiscoveryPath("/scim/ResourceTypes"))
+	assert.False(t, isDiscoveryPath("/scim/Users/1234"))
+}
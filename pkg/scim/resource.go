@@ -0,0 +1,33 @@
+package scim
+
+// Meta holds the resource metadata attributes that SCIM servers attach to
+// every resource representation, as defined in RFC 7643 §3.1.
+type Meta struct {
+	ResourceType string `json:"resourceType,omitempty"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+	Version      string `json:"version,omitempty"`
+}
+
+// CommonAttributes carries the attributes shared by every SCIM resource
+// type (RFC 7643 §3.1) and is intended to be embedded by concrete resource
+// types such as User and Group.
+type CommonAttributes struct {
+	Schemas    []string `json:"schemas,omitempty"`
+	ID         string   `json:"id,omitempty"`
+	ExternalID string   `json:"externalId,omitempty"`
+	Meta       Meta     `json:"meta,omitempty"`
+}
+
+// GetMeta returns the resource's metadata, satisfying the Resource
+// interface.
+func (c CommonAttributes) GetMeta() Meta {
+	return c.Meta
+}
+
+// Resource is implemented by every SCIM resource representation (User,
+// Group, ...) and is what the Client's CRUD methods operate against.
+type Resource interface {
+	GetMeta() Meta
+}
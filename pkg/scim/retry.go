@@ -0,0 +1,176 @@
+package scim
+
+import (
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// retryableMethods are the idempotent SCIM verbs retried by default;
+// POST is only retried when a RetryConfig opts in via RetryPOSTSearch,
+// since a bare POST may not be idempotent (e.g. resource creation).
+var retryableMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodPut:    true,
+	http.MethodDelete: true,
+	http.MethodHead:   true,
+}
+
+// RetryClassifier decides whether a round trip's response/error should be
+// retried and, when the response itself dictates a wait (e.g. a
+// Retry-After header), how long to wait. Returning a zero wait tells the
+// transport to compute its own full-jitter exponential backoff.
+type RetryClassifier func(*http.Response, error) (retry bool, wait time.Duration)
+
+// RetryConfig configures the Retry ClientOpt's backoff policy.
+type RetryConfig struct {
+	Base            time.Duration
+	Cap             time.Duration
+	MaxAttempts     int
+	RetryPOSTSearch bool
+	Classifier      RetryClassifier
+}
+
+// Retry wraps the Client's underlying RoundTripper with a retry policy for
+// idempotent SCIM methods (GET, PUT, DELETE, HEAD), plus POST when
+// cfg.RetryPOSTSearch is set (for /.search endpoints). It implements
+// full-jitter exponential backoff, honoring Retry-After on 429/503
+// responses, and only retries network errors, 429, and 5xx.
+func Retry(cfg RetryConfig) ClientOpt {
+	return func(c *clientCfg) error {
+		c.Retry = cfg
+		c.retryConfigSet = true
+		return nil
+	}
+}
+
+// defaultRetryClassifier retries network errors, 429, and any 5xx
+// response, parsing Retry-After (delta-seconds or HTTP-date) when present.
+func defaultRetryClassifier(resp *http.Response, err error) (bool, time.Duration) {
+	if err != nil {
+		return true, 0
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return false, 0
+	}
+	if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		return true, wait
+	}
+	return true, 0
+}
+
+// parseRetryAfter parses a Retry-After header value in either its
+// delta-seconds or HTTP-date form (RFC 7231 §7.1.3).
+func parseRetryAfter(val string) (time.Duration, bool) {
+	if val == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(val); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if when, err := http.ParseTime(val); err == nil {
+		if wait := time.Until(when); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// fullJitterBackoff implements the "full jitter" backoff from
+// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/:
+// sleep = rand(0, min(cap, base*2^attempt)). A maxWait <= 0 means
+// unbounded, as a zero BulkConfig.MaxOperations/MaxPayloadSize is.
+func fullJitterBackoff(base, maxWait time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	backoff := base << uint(attempt)
+	if backoff <= 0 {
+		backoff = time.Duration(math.MaxInt64 - 1)
+	}
+	if maxWait > 0 && backoff > maxWait {
+		backoff = maxWait
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// retryTransport is the http.RoundTripper installed by the Retry
+// ClientOpt.
+type retryTransport struct {
+	next http.RoundTripper
+	cfg  RetryConfig
+}
+
+func (t retryTransport) isRetryable(req *http.Request) bool {
+	if retryableMethods[req.Method] {
+		return true
+	}
+	return req.Method == http.MethodPost && t.cfg.RetryPOSTSearch &&
+		strings.HasSuffix(req.URL.Path, "/.search")
+}
+
+func (t retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if !t.isRetryable(req) {
+		return next.RoundTrip(req)
+	}
+
+	classify := t.cfg.Classifier
+	if classify == nil {
+		classify = defaultRetryClassifier
+	}
+	maxAttempts := t.cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	var resp *http.Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, berr := req.GetBody()
+			if berr != nil {
+				return nil, berr
+			}
+			req.Body = body
+		}
+
+		resp, err = next.RoundTrip(req)
+
+		retry, wait := classify(resp, err)
+		if !retry || attempt == maxAttempts-1 {
+			return resp, err
+		}
+
+		drainAndClose(resp)
+
+		if wait == 0 {
+			wait = fullJitterBackoff(t.cfg.Base, t.cfg.Cap, attempt)
+		} else if wait > t.cfg.Cap && t.cfg.Cap > 0 {
+			wait = t.cfg.Cap
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func drainAndClose(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,139 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		expOk  bool
+	}{
+		{"Empty", "", false},
+		{"Delta seconds", "2", true},
+		{"HTTP date", time.Now().Add(2 * time.Second).UTC().Format(http.TimeFormat), true},
+		{"Garbage", "not-a-date", false},
+	}
+	for idx := range tests {
+		test := tests[idx]
+		t.Run(test.name, func(t *testing.T) {
+			_, ok := parseRetryAfter(test.header)
+			assert.Equal(t, test.expOk, ok)
+		})
+	}
+}
+
+func TestDefaultRetryClassifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		err      error
+		expRetry bool
+	}{
+		{"Network error", nil, errors.New("boom"), true},
+		{"429", &http.Response{StatusCode: 429, Header: http.Header{}}, nil, true},
+		{"503", &http.Response{StatusCode: 503, Header: http.Header{}}, nil, true},
+		{"404", &http.Response{StatusCode: 404, Header: http.Header{}}, nil, false},
+		{"200", &http.Response{StatusCode: 200, Header: http.Header{}}, nil, false},
+	}
+	for idx := range tests {
+		test := tests[idx]
+		t.Run(test.name, func(t *testing.T) {
+			retry, _ := defaultRetryClassifier(test.resp, test.err)
+			assert.Equal(t, test.expRetry, retry)
+		})
+	}
+}
+
+func TestFullJitterBackoffZeroCapIsUnbounded(t *testing.T) {
+	base := time.Second
+	for attempt := 0; attempt < 10; attempt++ {
+		wait := fullJitterBackoff(base, 0, attempt)
+		assert.LessOrEqual(t, wait, base<<uint(attempt))
+	}
+
+	// A high attempt count would overflow base<<attempt; fullJitterBackoff
+	// must not panic and must still return within int64 bounds.
+	assert.NotPanics(t, func() {
+		fullJitterBackoff(base, 0, 100)
+	})
+}
+
+func TestRetryTransportRetriesUntilSuccess(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	transport := retryTransport{cfg: RetryConfig{Base: time.Millisecond, Cap: 5 * time.Millisecond, MaxAttempts: 5}}
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	assert.NoError(t, err)
+	req = req.WithContext(context.Background())
+
+	resp, err := transport.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetryTransportRebuildsBodyFromGetBody(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := make([]byte, r.ContentLength)
+		r.Body.Read(buf)
+		bodies = append(bodies, string(buf))
+		if len(bodies) < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	transport := retryTransport{cfg: RetryConfig{Base: time.Millisecond, Cap: 5 * time.Millisecond, MaxAttempts: 3}}
+	req, err := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("payload"))
+	assert.NoError(t, err)
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader("payload")), nil
+	}
+	req = req.WithContext(context.Background())
+
+	resp, err := transport.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"payload", "payload"}, bodies)
+}
+
+func TestRetryTransportHonorsContextCancellation(t *testing.T) {
+	transport := retryTransport{cfg: RetryConfig{Base: time.Hour, Cap: time.Hour, MaxAttempts: 5}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequest(http.MethodGet, "http://127.0.0.1:1", nil)
+	assert.NoError(t, err)
+	req = req.WithContext(ctx)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err = transport.RoundTrip(req)
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SearchRequest carries the RFC 7644 §3.4.2.2 query parameters used to
+// filter, sort, and page a ListResources call.
+type SearchRequest struct {
+	Filter             string
+	SortBy             string
+	SortOrder          string
+	Attributes         []string
+	ExcludedAttributes []string
+	StartIndex         int
+	Count              int
+}
+
+func (sr SearchRequest) query() url.Values {
+	q := url.Values{}
+	if sr.Filter != "" {
+		q.Set("filter", sr.Filter)
+	}
+	if sr.SortBy != "" {
+		q.Set("sortBy", sr.SortBy)
+	}
+	if sr.SortOrder != "" {
+		q.Set("sortOrder", sr.SortOrder)
+	}
+	for _, a := range sr.Attributes {
+		q.Add("attributes", a)
+	}
+	for _, a := range sr.ExcludedAttributes {
+		q.Add("excludedAttributes", a)
+	}
+	if sr.StartIndex > 0 {
+		q.Set("startIndex", fmt.Sprintf("%d", sr.StartIndex))
+	}
+	if sr.Count > 0 {
+		q.Set("count", fmt.Sprintf("%d", sr.Count))
+	}
+	return q
+}
+
+// ListResponse is the RFC 7644 §3.4.2 list response envelope. Resources
+// are left as raw JSON so that callers can unmarshal each one into the
+// concrete resource type they expect.
+type ListResponse struct {
+	Schemas      []string          `json:"schemas"`
+	TotalResults int               `json:"totalResults"`
+	ItemsPerPage int               `json:"itemsPerPage,omitempty"`
+	StartIndex   int               `json:"startIndex,omitempty"`
+	Resources    []json.RawMessage `json:"Resources,omitempty"`
+}
+
+// GetMeta satisfies Resource so that ListResponse can be decoded through
+// resourceOrError like any other resource representation.
+func (ListResponse) GetMeta() Meta {
+	return Meta{}
+}
+
+// ListResources issues a single paged GET against the given resource
+// type's endpoint. Callers that need to page through an entire result set
+// should prefer Iterate.
+func (c *Client) ListResources(ctx context.Context, resourceType string, sr SearchRequest) (ListResponse, error) {
+	reqURL := c.serviceURL() + "/" + resourceType
+	if q := sr.query(); len(q) > 0 {
+		reqURL += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, reqURL, nil)
+	if err != nil {
+		return ListResponse{}, err
+	}
+	req = req.WithContext(ctx)
+
+	var lr ListResponse
+	if err := c.resourceOrError(&lr, req); err != nil {
+		return ListResponse{}, err
+	}
+	return lr, nil
+}
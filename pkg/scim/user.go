@@ -0,0 +1,28 @@
+package scim
+
+// Name models the SCIM User "name" complex attribute (RFC 7643 §4.1.1).
+type Name struct {
+	Formatted       string `json:"formatted,omitempty"`
+	FamilyName      string `json:"familyName,omitempty"`
+	GivenName       string `json:"givenName,omitempty"`
+	MiddleName      string `json:"middleName,omitempty"`
+	HonorificPrefix string `json:"honorificPrefix,omitempty"`
+	HonorificSuffix string `json:"honorificSuffix,omitempty"`
+}
+
+// Email models a single entry of the SCIM User "emails" multi-valued
+// attribute (RFC 7643 §4.1.2).
+type Email struct {
+	Value   string `json:"value,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// User is the SCIM core User resource (RFC 7643 §4.1).
+type User struct {
+	CommonAttributes
+	UserName string  `json:"userName"`
+	Name     Name    `json:"name,omitempty"`
+	Emails   []Email `json:"emails,omitempty"`
+	Active   bool    `json:"active,omitempty"`
+}